@@ -0,0 +1,42 @@
+package contract
+
+import (
+	"github.com/renproject/multichain/api/address"
+	"github.com/renproject/pack"
+)
+
+// A LogFilterQuery describes a chain-agnostic filter over contract event
+// logs, mirroring the shape of an Ethereum `eth_getLogs` filter so that
+// implementations backed by an EVM JSON-RPC endpoint can translate it
+// directly. FromBlock/ToBlock are inclusive; a nil-equivalent zero value
+// for ToBlock should be interpreted by implementations as "latest".
+type LogFilterQuery struct {
+	FromBlock pack.U64
+	ToBlock   pack.U64
+	Addresses []address.Address
+	Topics    [][]pack.Bytes
+}
+
+// A Log is a single contract event log, returned by FilterLogs or
+// delivered over a log subscription.
+type Log struct {
+	Address     address.Address
+	Topics      []pack.Bytes
+	Data        pack.Bytes
+	BlockHash   pack.Bytes
+	BlockNumber pack.U64
+	TxHash      pack.Bytes
+	TxIndex     pack.U32
+	Index       pack.U32
+	Removed     bool
+}
+
+// A Subscription represents an ongoing streaming subscription (to logs, or
+// to new chain heads). Err delivers a single error if the subscription is
+// terminated by the underlying transport; Unsubscribe cancels the
+// subscription and closes the Err channel. The channel the subscription
+// delivers to is not closed, by Unsubscribe or otherwise.
+type Subscription interface {
+	Err() <-chan error
+	Unsubscribe()
+}
@@ -0,0 +1,58 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is the set of JSON-RPC operations that `Client` needs from an
+// Ethereum node. `*ethclient.Client` is the default implementation, but a
+// `Client` can be pointed at any other implementation (a reconnecting
+// WebSocket transport, a failover pool of nodes, a test double, ...) via
+// NewClientWithBackend, without every caller re-implementing transport
+// concerns like reconnects or failover.
+type Backend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// BatchBackend is implemented by a Backend that can pipeline multiple
+// JSON-RPC calls into a single round trip via rpc.Client.BatchCallContext,
+// instead of issuing each one sequentially. Client type-asserts its
+// backend against BatchBackend to serve TxBatch, AccountBalanceBatch and
+// AccountNonceBatch; a Backend that doesn't implement it causes those
+// methods to return an error.
+type BatchBackend interface {
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
+
+// ethClientBackend adapts *ethclient.Client to BatchBackend, by delegating
+// to the *rpc.Client it was dialed from (exposed via ethclient.Client's
+// Client method). This is the default Backend used by NewClient.
+type ethClientBackend struct {
+	*ethclient.Client
+}
+
+// BatchCallContext implements BatchBackend.
+func (b ethClientBackend) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	return b.Client.Client().BatchCallContext(ctx, batch)
+}
@@ -0,0 +1,255 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/renproject/multichain/api/account"
+	"github.com/renproject/multichain/api/address"
+	"github.com/renproject/pack"
+)
+
+// rpcTxExtraInfo holds the `eth_getTransactionByHash` response fields that
+// `types.Transaction` itself doesn't expose, but that are needed to tell a
+// pending transaction (null blockHash) apart from a mined one.
+type rpcTxExtraInfo struct {
+	BlockHash *common.Hash `json:"blockHash,omitempty"`
+}
+
+// rpcTransaction decodes the same `eth_getTransactionByHash` response as
+// `ethclient.Client.TransactionByHash`.
+type rpcTransaction struct {
+	tx *types.Transaction
+	rpcTxExtraInfo
+}
+
+func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
+	if err := json.Unmarshal(msg, &tx.tx); err != nil {
+		return err
+	}
+	return json.Unmarshal(msg, &tx.rpcTxExtraInfo)
+}
+
+// TxResult is one element of the slice TxBatch returns: either Tx and
+// Confirmations, or Err if that particular transaction couldn't be
+// resolved (not found, reverted, or an RPC error specific to it). A
+// caller scanning hundreds of txIDs (e.g. an indexer polling pending
+// txs) should expect Err on some elements as routine, not exceptional,
+// and keep processing the rest of the batch.
+type TxResult struct {
+	Tx            account.Tx
+	Confirmations pack.U64
+	Err           error
+}
+
+// TxBatch returns one TxResult per txID, in the same order as txIDs,
+// pipelining the underlying `eth_getTransactionByHash` and
+// `eth_getTransactionReceipt` calls into a single batched RPC request
+// instead of looking each one up sequentially via Tx. A problem with one
+// txID (not found, reverted, an RPC error for that element) is reported
+// on its own TxResult.Err and does not prevent the rest of the batch
+// from resolving; the returned error is reserved for failures that
+// invalidate the whole call (the backend doesn't support batching, the
+// batched round trip itself failed, the head header couldn't be
+// fetched). The backend must implement BatchBackend.
+func (client *Client) TxBatch(ctx context.Context, txIDs []pack.Bytes) ([]TxResult, error) {
+	batcher, ok := client.backend.(BatchBackend)
+	if !ok {
+		return nil, fmt.Errorf("backend %T does not support batched calls", client.backend)
+	}
+	if len(txIDs) == 0 {
+		return nil, nil
+	}
+
+	chainID, err := client.cachedChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching chain ID: %v", err)
+	}
+
+	txResults := make([]rpcTransaction, len(txIDs))
+	receiptResults := make([]*types.Receipt, len(txIDs))
+	batch := make([]rpc.BatchElem, 0, 2*len(txIDs))
+	for i, txID := range txIDs {
+		hash := common.BytesToHash(txID)
+		batch = append(batch,
+			rpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{hash}, Result: &txResults[i]},
+			rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receiptResults[i]},
+		)
+	}
+	if err := batcher.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("batch fetching txs: %v", err)
+	}
+
+	// Confirmations for mined txs are relative to the current chain head,
+	// so one extra (unbatched) call is needed regardless of how many txs
+	// were requested.
+	header, err := client.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching header: %v", err)
+	}
+
+	results := make([]TxResult, len(txIDs))
+	for i, txID := range txIDs {
+		if batch[2*i].Error != nil {
+			results[i].Err = fmt.Errorf("fetching tx by hash '%v': %v", txID, batch[2*i].Error)
+			continue
+		}
+		if txResults[i].tx == nil {
+			results[i].Err = fmt.Errorf("tx '%v' not found", txID)
+			continue
+		}
+
+		pendingTx := Tx{
+			ethTx:  txResults[i].tx,
+			signer: types.LatestSignerForChainID(chainID),
+		}
+
+		// Still pending: no receipt to check yet.
+		if txResults[i].BlockHash == nil {
+			results[i] = TxResult{Tx: &pendingTx, Confirmations: pack.NewU64(0)}
+			continue
+		}
+
+		if batch[2*i+1].Error != nil {
+			results[i].Err = fmt.Errorf("fetching recipt for tx %v : %v", txID, batch[2*i+1].Error)
+			continue
+		}
+		receipt := receiptResults[i]
+		if receipt == nil {
+			results[i] = TxResult{Tx: &pendingTx, Confirmations: pack.NewU64(0)}
+			continue
+		}
+		if receipt.Status == 0 {
+			if reason := client.revertReason(ctx, txID); reason != "" {
+				results[i].Err = fmt.Errorf("tx %v reverted: %v", txID, reason)
+			} else {
+				results[i].Err = fmt.Errorf("tx %v reverted, reciept status 0", txID)
+			}
+			continue
+		}
+
+		results[i] = TxResult{
+			Tx:            &Tx{txResults[i].tx, types.LatestSignerForChainID(chainID)},
+			Confirmations: pack.NewU64(header.Number.Uint64() - receipt.BlockNumber.Uint64()),
+		}
+	}
+	return results, nil
+}
+
+// BalanceResult is one element of the slice AccountBalanceBatch returns:
+// either Balance, or Err if that particular address's balance couldn't be
+// fetched. A caller scanning many addresses should expect Err on some
+// elements as routine, not exceptional, and keep processing the rest of
+// the batch.
+type BalanceResult struct {
+	Balance pack.U256
+	Err     error
+}
+
+// AccountBalanceBatch returns one BalanceResult per address, in the same
+// order as addrs, pipelining the underlying `eth_getBalance` calls into a
+// single batched RPC request. A problem with one address's balance (an
+// RPC error specific to that element) is reported on its own
+// BalanceResult.Err and does not prevent the rest of the batch from
+// resolving; the returned error is reserved for failures that invalidate
+// the whole call (the backend doesn't support batching, a malformed
+// address, the batched round trip itself failed). The backend must
+// implement BatchBackend.
+func (client *Client) AccountBalanceBatch(ctx context.Context, addrs []address.Address) ([]BalanceResult, error) {
+	batcher, ok := client.backend.(BatchBackend)
+	if !ok {
+		return nil, fmt.Errorf("backend %T does not support batched calls", client.backend)
+	}
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	results := make([]hexutil.Big, len(addrs))
+	batch := make([]rpc.BatchElem, len(addrs))
+	for i, addr := range addrs {
+		targetAddr, err := NewAddressFromHex(string(pack.String(addr)))
+		if err != nil {
+			return nil, fmt.Errorf("bad address '%v': %v", addr, err)
+		}
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{common.Address(targetAddr), "latest"},
+			Result: &results[i],
+		}
+	}
+	if err := batcher.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("batch fetching balances: %v", err)
+	}
+
+	balances := make([]BalanceResult, len(addrs))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			balances[i].Err = fmt.Errorf("failed to get balance for '%v': %v", addrs[i], elem.Error)
+			continue
+		}
+		balances[i].Balance = pack.NewU256FromInt((*big.Int)(&results[i]))
+	}
+	return balances, nil
+}
+
+// NonceResult is one element of the slice AccountNonceBatch returns:
+// either Nonce, or Err if that particular address's nonce couldn't be
+// fetched. A caller scanning many addresses should expect Err on some
+// elements as routine, not exceptional, and keep processing the rest of
+// the batch.
+type NonceResult struct {
+	Nonce pack.U256
+	Err   error
+}
+
+// AccountNonceBatch returns one NonceResult per address, in the same
+// order as addrs, pipelining the underlying `eth_getTransactionCount`
+// calls into a single batched RPC request. A problem with one address's
+// nonce (an RPC error specific to that element) is reported on its own
+// NonceResult.Err and does not prevent the rest of the batch from
+// resolving; the returned error is reserved for failures that invalidate
+// the whole call (the backend doesn't support batching, a malformed
+// address, the batched round trip itself failed). The backend must
+// implement BatchBackend.
+func (client *Client) AccountNonceBatch(ctx context.Context, addrs []address.Address) ([]NonceResult, error) {
+	batcher, ok := client.backend.(BatchBackend)
+	if !ok {
+		return nil, fmt.Errorf("backend %T does not support batched calls", client.backend)
+	}
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	results := make([]hexutil.Uint64, len(addrs))
+	batch := make([]rpc.BatchElem, len(addrs))
+	for i, addr := range addrs {
+		targetAddr, err := NewAddressFromHex(string(pack.String(addr)))
+		if err != nil {
+			return nil, fmt.Errorf("bad address '%v': %v", addr, err)
+		}
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getTransactionCount",
+			Args:   []interface{}{common.Address(targetAddr), "latest"},
+			Result: &results[i],
+		}
+	}
+	if err := batcher.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("batch fetching nonces: %v", err)
+	}
+
+	nonces := make([]NonceResult, len(addrs))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			nonces[i].Err = fmt.Errorf("failed to get nonce for '%v': %v", addrs[i], elem.Error)
+			continue
+		}
+		nonces[i].Nonce = pack.NewU256FromU64(pack.NewU64(uint64(results[i])))
+	}
+	return nonces, nil
+}
@@ -3,11 +3,13 @@ package ethereum
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sync"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/renproject/multichain/api/account"
 	"github.com/renproject/multichain/api/address"
 	"github.com/renproject/multichain/api/contract"
@@ -22,23 +24,76 @@ const (
 
 // Client holds the underlying RPC client instance.
 type Client struct {
-	ethClient *ethclient.Client
+	backend Backend
+	locker  *AddrLocker
+
+	chainIDMu sync.Mutex
+	chainID   *big.Int
+
+	geth *GethClient
 }
 
-// NewClient creates and returns a new JSON-RPC client to the Ethereum node
+// NewClient creates and returns a new JSON-RPC client to the Ethereum node,
+// using the default `ethclient.Client` backend.
 func NewClient(rpcURL string) (*Client, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf(fmt.Sprintf("dialing url: %v", rpcURL))
 	}
+	return NewClientWithBackend(ethClientBackend{client}), nil
+}
+
+// NewClientWithBackend creates a Client backed by an arbitrary Backend
+// implementation, such as WSBackend or FailoverBackend, for callers that
+// need a transport other than the default HTTP/WS `ethclient.Client`.
+func NewClientWithBackend(backend Backend) *Client {
 	return &Client{
-		client,
-	}, nil
+		backend: backend,
+		locker:  NewAddrLocker(),
+	}
+}
+
+// cachedChainID returns the connected chain's ID, fetching it from the
+// backend on the first call and caching it thereafter: a node's chain ID
+// cannot change over the lifetime of a Client, so every later caller
+// (including TxBatch) can reuse the cached value instead of spending a
+// round trip on it.
+func (client *Client) cachedChainID(ctx context.Context) (*big.Int, error) {
+	client.chainIDMu.Lock()
+	defer client.chainIDMu.Unlock()
+
+	if client.chainID == nil {
+		chainID, err := client.backend.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		client.chainID = chainID
+	}
+	return client.chainID, nil
+}
+
+// WithGethClient attaches geth to client, for use by Tx and TxBatch: when
+// a transaction has reverted, they trace it via geth and include the
+// revert reason in their error, instead of the opaque "... reverted,
+// reciept status 0". It returns client so it can be chained onto
+// NewClient/NewClientWithBackend.
+func (client *Client) WithGethClient(geth *GethClient) *Client {
+	client.geth = geth
+	return client
+}
+
+// revertReason returns the human-readable reason txID reverted with, or ""
+// if client has no GethClient attached, or tracing it didn't turn one up.
+func (client *Client) revertReason(ctx context.Context, txID pack.Bytes) string {
+	if client.geth == nil {
+		return ""
+	}
+	return client.geth.revertReason(ctx, txID)
 }
 
 // LatestBlock returns the block number at the current chain head.
 func (client *Client) LatestBlock(ctx context.Context) (pack.U64, error) {
-	header, err := client.ethClient.HeaderByNumber(ctx, nil)
+	header, err := client.backend.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return pack.NewU64(0), fmt.Errorf("fetching header: %v", err)
 	}
@@ -48,25 +103,27 @@ func (client *Client) LatestBlock(ctx context.Context) (pack.U64, error) {
 // Tx returns the transaction uniquely identified by the given transaction
 // hash. It also returns the number of confirmations for the transaction.
 func (client *Client) Tx(ctx context.Context, txID pack.Bytes) (account.Tx, pack.U64, error) {
-	tx, pending, err := client.ethClient.TransactionByHash(ctx, common.BytesToHash(txID))
+	tx, pending, err := client.backend.TransactionByHash(ctx, common.BytesToHash(txID))
 	if err != nil {
 		return nil, pack.NewU64(0), fmt.Errorf(fmt.Sprintf("fetching tx by hash '%v': %v", txID, err))
 	}
-	chainID, err := client.ethClient.ChainID(ctx)
+	chainID, err := client.cachedChainID(ctx)
 	if err != nil {
 		return nil, pack.NewU64(0), fmt.Errorf("fetching chain ID: %v", err)
 	}
 
-	// If the transaction is still pending, use default EIP-155 signer.
+	// If the transaction is still pending, pick the signer matching its
+	// own type so that EIP-1559 (and EIP-2930) pending txs are handled
+	// correctly, not just legacy EIP-155 ones.
 	pendingTx := Tx{
 		ethTx:  tx,
-		signer: types.NewEIP155Signer(chainID),
+		signer: types.LatestSignerForChainID(chainID),
 	}
 	if pending {
 		return &pendingTx, 0, nil
 	}
 
-	receipt, err := client.ethClient.TransactionReceipt(ctx, common.BytesToHash(txID))
+	receipt, err := client.backend.TransactionReceipt(ctx, common.BytesToHash(txID))
 	if err != nil {
 		return nil, pack.NewU64(0), fmt.Errorf("fetching recipt for tx %v : %v", txID, err)
 	}
@@ -78,45 +135,22 @@ func (client *Client) Tx(ctx context.Context, txID pack.Bytes) (account.Tx, pack
 
 	// reverted tx
 	if receipt.Status == 0 {
+		if reason := client.revertReason(ctx, txID); reason != "" {
+			return nil, pack.NewU64(0), fmt.Errorf("tx %v reverted: %v", txID, reason)
+		}
 		return nil, pack.NewU64(0), fmt.Errorf("tx %v reverted, reciept status 0", txID)
 	}
 
-	// tx confirmed
+	// tx confirmed. types.LatestSignerForChainID picks the signer that
+	// matches the transaction's own type (legacy, EIP-2930 or EIP-1559),
+	// so it is correct uniformly across pre- and post-London chains and
+	// forks without enumerating chain IDs here.
 	confirmedTx := Tx{
 		tx,
 		types.LatestSignerForChainID(chainID),
 	}
 
-	// select signer for chain
-	switch chainID.Uint64() {
-	case 0:
-		confirmedTx = Tx{
-			tx,
-			types.MakeSigner(params.YoloV3ChainConfig, receipt.BlockNumber),
-		}
-	case 1:
-		confirmedTx = Tx{
-			tx,
-			types.MakeSigner(params.MainnetChainConfig, receipt.BlockNumber),
-		}
-	case 3:
-		confirmedTx = Tx{
-			tx,
-			types.MakeSigner(params.RopstenChainConfig, receipt.BlockNumber),
-		}
-	case 4:
-		confirmedTx = Tx{
-			tx,
-			types.MakeSigner(params.RinkebyChainConfig, receipt.BlockNumber),
-		}
-	case 5:
-		confirmedTx = Tx{
-			tx,
-			types.MakeSigner(params.GoerliChainConfig, receipt.BlockNumber),
-		}
-	}
-
-	header, err := client.ethClient.HeaderByNumber(ctx, nil)
+	header, err := client.backend.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return nil, pack.NewU64(0), fmt.Errorf("fetching header : %v", err)
 	}
@@ -124,11 +158,15 @@ func (client *Client) Tx(ctx context.Context, txID pack.Bytes) (account.Tx, pack
 	return &confirmedTx, pack.NewU64(header.Number.Uint64() - receipt.BlockNumber.Uint64()), nil
 }
 
-// SubmitTx to the underlying blockchain network.
+// SubmitTx to the underlying blockchain network. It does not touch the
+// sender's nonce lock: NextNonce returns its own release func for the
+// caller to hold onto (typically via `defer`) for exactly that purpose,
+// since SubmitTx has no way to tell which NextNonce call, if any, the
+// given tx's nonce was reserved by.
 func (client *Client) SubmitTx(ctx context.Context, tx account.Tx) error {
 	switch tx := tx.(type) {
 	case *Tx:
-		err := client.ethClient.SendTransaction(ctx, tx.ethTx)
+		err := client.backend.SendTransaction(ctx, tx.ethTx)
 		if err != nil {
 			return fmt.Errorf(fmt.Sprintf("sending transaction '%v': %v", tx.Hash(), err))
 		}
@@ -145,7 +183,7 @@ func (client *Client) AccountNonce(ctx context.Context, addr address.Address) (p
 	if err != nil {
 		return pack.U256{}, fmt.Errorf("bad to address '%v': %v", addr, err)
 	}
-	nonce, err := client.ethClient.NonceAt(ctx, common.Address(targetAddr), nil)
+	nonce, err := client.backend.NonceAt(ctx, common.Address(targetAddr), nil)
 	if err != nil {
 		return pack.U256{}, fmt.Errorf("failed to get nonce for '%v': %v", addr, err)
 	}
@@ -153,13 +191,43 @@ func (client *Client) AccountNonce(ctx context.Context, addr address.Address) (p
 	return pack.NewU256FromU64(pack.NewU64(nonce)), nil
 }
 
+// NextNonce atomically reserves the next nonce to use for addr. It reads
+// the pending nonce (via PendingNonceAt, so that already-broadcast-but
+// -unmined transactions are counted) and locks addr so that concurrent
+// callers building a transaction for the same sender don't race on
+// nonce assignment.
+//
+// The returned release func unlocks addr again, and the caller must call
+// it no matter how the rest of the build goes (calls after the first are
+// no-ops): on the happy path, after the built transaction has been passed
+// to SubmitTx; on any abandoned path (gas estimation fails, signing
+// fails, ctx is cancelled), by deferring it right after NextNonce
+// returns. Without that, addr stays locked forever and every later
+// NextNonce call for it blocks indefinitely.
+func (client *Client) NextNonce(ctx context.Context, addr address.Address) (pack.U256, func(), error) {
+	targetAddr, err := NewAddressFromHex(string(pack.String(addr)))
+	if err != nil {
+		return pack.U256{}, func() {}, fmt.Errorf("bad to address '%v': %v", addr, err)
+	}
+	ethAddr := common.Address(targetAddr)
+
+	release := client.locker.Lock(ethAddr)
+	nonce, err := client.backend.PendingNonceAt(ctx, ethAddr)
+	if err != nil {
+		release()
+		return pack.U256{}, func() {}, fmt.Errorf("failed to get pending nonce for '%v': %v", addr, err)
+	}
+
+	return pack.NewU256FromU64(pack.NewU64(nonce)), release, nil
+}
+
 // AccountBalance returns the account balancee for a given address.
 func (client *Client) AccountBalance(ctx context.Context, addr address.Address) (pack.U256, error) {
 	targetAddr, err := NewAddressFromHex(string(pack.String(addr)))
 	if err != nil {
 		return pack.U256{}, fmt.Errorf("bad to address '%v': %v", addr, err)
 	}
-	balance, err := client.ethClient.BalanceAt(ctx, common.Address(targetAddr), nil)
+	balance, err := client.backend.BalanceAt(ctx, common.Address(targetAddr), nil)
 	if err != nil {
 		return pack.U256{}, fmt.Errorf("failed to get balance for '%v': %v", addr, err)
 	}
@@ -179,5 +247,5 @@ func (client *Client) CallContract(ctx context.Context, program address.Address,
 		To:   &addr,
 		Data: calldata,
 	}
-	return client.ethClient.CallContract(ctx, callMsg, nil)
+	return client.backend.CallContract(ctx, callMsg, nil)
 }
@@ -0,0 +1,356 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// healthProbeTimeout bounds how long HealthCheck waits on any one
+	// endpoint's ChainID/HeaderByNumber probe, independent of ctx: ctx
+	// commonly carries no deadline here, and a hung node is exactly the
+	// "downed node" case HealthCheck exists to route around.
+	healthProbeTimeout = 10 * time.Second
+
+	// maxHealthyHeightLag is how far behind the highest-reporting
+	// endpoint's block height another endpoint may trail and still count
+	// as healthy. A node that keeps answering eth_chainId correctly but
+	// has stalled well behind the chain head is reachable, not caught up,
+	// and shouldn't keep receiving round-robin traffic.
+	maxHealthyHeightLag = 3
+)
+
+// FailoverBackend is a Backend implementation backed by a pool of nodes
+// (e.g. a mix of Infura/Alchemy/self-hosted endpoints). Requests are sent
+// round-robin to the endpoints that last passed a health check. Only
+// HealthCheck changes which endpoints count as healthy: a request that
+// errors is returned to the caller as is, without marking its endpoint
+// unhealthy or retrying it on another endpoint.
+type FailoverBackend struct {
+	endpoints []*ethclient.Client
+
+	mu      sync.Mutex
+	next    int
+	healthy []bool
+}
+
+// NewFailoverBackend dials every url in urls and returns a FailoverBackend
+// that round-robins across them. All endpoints start out marked healthy;
+// call HealthCheck before serving traffic to weed out any that are
+// unreachable or on the wrong chain.
+func NewFailoverBackend(urls []string) (*FailoverBackend, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no endpoints provided")
+	}
+
+	endpoints := make([]*ethclient.Client, len(urls))
+	for i, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			return nil, fmt.Errorf("dialing url '%v': %v", url, err)
+		}
+		endpoints[i] = client
+	}
+
+	healthy := make([]bool, len(endpoints))
+	for i := range healthy {
+		healthy[i] = true
+	}
+
+	return &FailoverBackend{
+		endpoints: endpoints,
+		healthy:   healthy,
+	}, nil
+}
+
+// endpointStatus is the result of probing a single endpoint during
+// HealthCheck.
+type endpointStatus struct {
+	chainID *big.Int
+	height  uint64
+	ok      bool
+}
+
+// votedHealthy decides, from each endpoint's probed status, which ones to
+// keep serving traffic: a probe that errored is unhealthy outright;
+// among the ones that responded, only those reporting the chain ID most
+// endpoints agree on are kept, so a node that's been quietly pointed at
+// the wrong network doesn't get traffic just because it's reachable; and
+// of those, only ones within maxHealthyHeightLag of the highest reported
+// height are kept, so a node that's fallen behind the chain head doesn't
+// get traffic just because it's on the right network. It is pulled out
+// of HealthCheck as a pure function of the probed statuses so the voting
+// logic can be tested without dialing anything.
+func votedHealthy(statuses []endpointStatus) []bool {
+	chainIDCounts := map[string]int{}
+	var maxHeight uint64
+	for _, s := range statuses {
+		if s.ok {
+			chainIDCounts[s.chainID.String()]++
+			if s.height > maxHeight {
+				maxHeight = s.height
+			}
+		}
+	}
+
+	majorityChainID := ""
+	majorityCount := 0
+	for chainID, count := range chainIDCounts {
+		if count > majorityCount {
+			majorityChainID, majorityCount = chainID, count
+		}
+	}
+
+	healthy := make([]bool, len(statuses))
+	for i, s := range statuses {
+		healthy[i] = s.ok && s.chainID.String() == majorityChainID && maxHeight-s.height <= maxHealthyHeightLag
+	}
+	return healthy
+}
+
+// HealthCheck queries every endpoint's chain ID and latest block height.
+// Endpoints that don't respond, that report a chain ID different from the
+// majority, or that have fallen more than maxHealthyHeightLag behind the
+// highest-reporting endpoint, are marked unhealthy and excluded from the
+// round-robin until the next HealthCheck. It returns an error only if no
+// endpoint is healthy afterwards. Endpoints are probed concurrently, each
+// bounded by its own healthProbeTimeout, so one endpoint hanging (e.g. a
+// downed node, with ctx carrying no deadline) cannot delay the result of
+// the others.
+func (b *FailoverBackend) HealthCheck(ctx context.Context) error {
+	statuses := make([]endpointStatus, len(b.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range b.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *ethclient.Client) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+			defer cancel()
+			chainID, err := endpoint.ChainID(ctx)
+			if err != nil {
+				return
+			}
+			header, err := endpoint.HeaderByNumber(ctx, nil)
+			if err != nil {
+				return
+			}
+			statuses[i] = endpointStatus{chainID: chainID, height: header.Number.Uint64(), ok: true}
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	healthy := votedHealthy(statuses)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	numHealthy := 0
+	for i, ok := range healthy {
+		b.healthy[i] = ok
+		if ok {
+			numHealthy++
+		}
+	}
+	if numHealthy == 0 {
+		return fmt.Errorf("no healthy endpoints")
+	}
+	return nil
+}
+
+// pick returns the next healthy endpoint in round-robin order.
+func (b *FailoverBackend) pick() (*ethclient.Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := 0; i < len(b.endpoints); i++ {
+		idx := (b.next + i) % len(b.endpoints)
+		if b.healthy[idx] {
+			b.next = (idx + 1) % len(b.endpoints)
+			return b.endpoints[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("no healthy endpoints")
+}
+
+// HeaderByNumber implements Backend.
+func (b *FailoverBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.HeaderByNumber(ctx, number)
+}
+
+// TransactionByHash implements Backend.
+func (b *FailoverBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, false, err
+	}
+	return endpoint.TransactionByHash(ctx, hash)
+}
+
+// TransactionReceipt implements Backend.
+func (b *FailoverBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.TransactionReceipt(ctx, hash)
+}
+
+// SendTransaction implements Backend. It broadcasts to every healthy
+// endpoint rather than just one, so that the transaction still propagates
+// if the round-robin pick happens to be slow to relay it.
+func (b *FailoverBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	endpoints := make([]*ethclient.Client, 0, len(b.endpoints))
+	for i, endpoint := range b.endpoints {
+		if b.healthy[i] {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no healthy endpoints")
+	}
+
+	var lastErr error
+	sent := 0
+	for _, endpoint := range endpoints {
+		if err := endpoint.SendTransaction(ctx, tx); err != nil {
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return lastErr
+	}
+	return nil
+}
+
+// NonceAt implements Backend.
+func (b *FailoverBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return 0, err
+	}
+	return endpoint.NonceAt(ctx, account, blockNumber)
+}
+
+// PendingNonceAt implements Backend.
+func (b *FailoverBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return 0, err
+	}
+	return endpoint.PendingNonceAt(ctx, account)
+}
+
+// BalanceAt implements Backend.
+func (b *FailoverBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.BalanceAt(ctx, account, blockNumber)
+}
+
+// CallContract implements Backend.
+func (b *FailoverBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.CallContract(ctx, msg, blockNumber)
+}
+
+// ChainID implements Backend.
+func (b *FailoverBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.ChainID(ctx)
+}
+
+// EstimateGas implements Backend.
+func (b *FailoverBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return 0, err
+	}
+	return endpoint.EstimateGas(ctx, msg)
+}
+
+// SuggestGasPrice implements Backend.
+func (b *FailoverBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap implements Backend.
+func (b *FailoverBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.SuggestGasTipCap(ctx)
+}
+
+// FilterLogs implements Backend.
+func (b *FailoverBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.FilterLogs(ctx, query)
+}
+
+// BatchCallContext implements BatchBackend by sending the whole batch to a
+// single picked endpoint, rather than round-robining element by element:
+// a JSON-RPC batch is one HTTP request, and is not splittable across nodes.
+func (b *FailoverBackend) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	endpoint, err := b.pick()
+	if err != nil {
+		return err
+	}
+	return endpoint.Client().BatchCallContext(ctx, batch)
+}
+
+// SubscribeFilterLogs implements Backend. Subscriptions are not
+// failed-over: they are pinned to whichever healthy endpoint is picked
+// at subscribe time, since a subscription already in flight on one node
+// can't be transparently migrated to another.
+func (b *FailoverBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.SubscribeFilterLogs(ctx, query, ch)
+}
+
+// SubscribeNewHead implements Backend. See SubscribeFilterLogs for why
+// subscriptions are pinned rather than failed-over.
+func (b *FailoverBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	endpoint, err := b.pick()
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.SubscribeNewHead(ctx, ch)
+}
@@ -0,0 +1,113 @@
+package ethereum
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func TestVotedHealthyMajorityWins(t *testing.T) {
+	statuses := []endpointStatus{
+		{chainID: big.NewInt(1), ok: true},
+		{chainID: big.NewInt(1), ok: true},
+		{chainID: big.NewInt(2), ok: true}, // wrong chain, outvoted
+		{ok: false},                        // errored probe
+	}
+
+	got := votedHealthy(statuses)
+	want := []bool{true, true, false, false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("votedHealthy(%+v) = %v, want %v", statuses, got, want)
+	}
+}
+
+func TestVotedHealthyExcludesLaggingHeight(t *testing.T) {
+	statuses := []endpointStatus{
+		{chainID: big.NewInt(1), height: 100, ok: true},
+		{chainID: big.NewInt(1), height: 99, ok: true},                        // within maxHealthyHeightLag
+		{chainID: big.NewInt(1), height: 100 - maxHealthyHeightLag - 1, ok: true}, // lagging too far behind
+	}
+
+	got := votedHealthy(statuses)
+	want := []bool{true, true, false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("votedHealthy(%+v) = %v, want %v", statuses, got, want)
+	}
+}
+
+func TestVotedHealthyAllUnhealthy(t *testing.T) {
+	statuses := []endpointStatus{{ok: false}, {ok: false}}
+	got := votedHealthy(statuses)
+	want := []bool{false, false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("votedHealthy(%+v) = %v, want %v", statuses, got, want)
+	}
+}
+
+func newTestFailoverBackend(n int) *FailoverBackend {
+	endpoints := make([]*ethclient.Client, n)
+	healthy := make([]bool, n)
+	for i := range healthy {
+		endpoints[i] = new(ethclient.Client) // distinct, unused pointer: pick() never dials it
+		healthy[i] = true
+	}
+	return &FailoverBackend{endpoints: endpoints, healthy: healthy}
+}
+
+func TestFailoverBackendPickRoundRobin(t *testing.T) {
+	b := newTestFailoverBackend(3)
+
+	var picks []int
+	for i := 0; i < 6; i++ {
+		endpoint, err := b.pick()
+		if err != nil {
+			t.Fatalf("pick() returned error: %v", err)
+		}
+		for idx, e := range b.endpoints {
+			if e == endpoint {
+				picks = append(picks, idx)
+				break
+			}
+		}
+	}
+
+	want := []int{0, 1, 2, 0, 1, 2}
+	if !reflect.DeepEqual(picks, want) {
+		t.Fatalf("pick() order = %v, want %v", picks, want)
+	}
+}
+
+func TestFailoverBackendPickSkipsUnhealthy(t *testing.T) {
+	b := newTestFailoverBackend(3)
+	b.healthy[1] = false
+
+	var picks []int
+	for i := 0; i < 4; i++ {
+		endpoint, err := b.pick()
+		if err != nil {
+			t.Fatalf("pick() returned error: %v", err)
+		}
+		for idx, e := range b.endpoints {
+			if e == endpoint {
+				picks = append(picks, idx)
+				break
+			}
+		}
+	}
+
+	want := []int{0, 2, 0, 2}
+	if !reflect.DeepEqual(picks, want) {
+		t.Fatalf("pick() order = %v, want %v", picks, want)
+	}
+}
+
+func TestFailoverBackendPickNoHealthyEndpoints(t *testing.T) {
+	b := newTestFailoverBackend(2)
+	b.healthy[0], b.healthy[1] = false, false
+
+	if _, err := b.pick(); err == nil {
+		t.Fatal("pick() returned nil error with no healthy endpoints")
+	}
+}
@@ -0,0 +1,153 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/renproject/multichain/api/address"
+	"github.com/renproject/multichain/api/contract"
+	"github.com/renproject/pack"
+)
+
+// FilterLogs returns the contract event logs that match the given query.
+// It wraps `ethclient.Client.FilterLogs` so that callers can query
+// historical logs without polling `Tx` or `LatestBlock` in a loop.
+func (client *Client) FilterLogs(ctx context.Context, query contract.LogFilterQuery) ([]contract.Log, error) {
+	gethQuery, err := toGethFilterQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("building filter query: %v", err)
+	}
+	logs, err := client.backend.FilterLogs(ctx, gethQuery)
+	if err != nil {
+		return nil, fmt.Errorf("filtering logs: %v", err)
+	}
+
+	results := make([]contract.Log, len(logs))
+	for i := range logs {
+		results[i] = fromGethLog(logs[i])
+	}
+	return results, nil
+}
+
+// SubscribeLogs streams contract event logs matching the given query to
+// ch, for as long as the returned Subscription is active. It wraps
+// `ethclient.Client.SubscribeFilterLogs`, which requires the client to be
+// dialed against a WebSocket (or IPC) endpoint.
+func (client *Client) SubscribeLogs(ctx context.Context, query contract.LogFilterQuery, ch chan<- contract.Log) (contract.Subscription, error) {
+	gethQuery, err := toGethFilterQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("building filter query: %v", err)
+	}
+	gethLogs := make(chan types.Log)
+	sub, err := client.backend.SubscribeFilterLogs(ctx, gethQuery, gethLogs)
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to logs: %v", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case log := <-gethLogs:
+				select {
+				case ch <- fromGethLog(log):
+				case <-sub.Err():
+					return
+				}
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// SubscribeNewHeads streams the block number of every new chain head to
+// ch, for as long as the returned Subscription is active. It wraps
+// `ethclient.Client.SubscribeNewHead`, which requires the client to be
+// dialed against a WebSocket (or IPC) endpoint.
+func (client *Client) SubscribeNewHeads(ctx context.Context, ch chan<- pack.U64) (contract.Subscription, error) {
+	headers := make(chan *types.Header)
+	sub, err := client.backend.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to new heads: %v", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case header := <-headers:
+				select {
+				case ch <- pack.NewU64(header.Number.Uint64()):
+				case <-sub.Err():
+					return
+				}
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// toGethFilterQuery converts a chain-agnostic LogFilterQuery into the
+// `go-ethereum` FilterQuery expected by `ethclient.Client`. A zero
+// ToBlock is translated to a nil upper bound, matching the "latest"
+// default documented on LogFilterQuery.
+func toGethFilterQuery(query contract.LogFilterQuery) (ethereum.FilterQuery, error) {
+	addrs := make([]common.Address, len(query.Addresses))
+	for i, addr := range query.Addresses {
+		targetAddr, err := NewAddressFromHex(string(pack.String(addr)))
+		if err != nil {
+			return ethereum.FilterQuery{}, fmt.Errorf("bad address '%v': %v", addr, err)
+		}
+		addrs[i] = common.Address(targetAddr)
+	}
+
+	topics := make([][]common.Hash, len(query.Topics))
+	for i, topicSet := range query.Topics {
+		hashes := make([]common.Hash, len(topicSet))
+		for j, topic := range topicSet {
+			hashes[j] = common.BytesToHash(topic)
+		}
+		topics[i] = hashes
+	}
+
+	var toBlock *big.Int
+	if query.ToBlock.Uint64() != 0 {
+		toBlock = new(big.Int).SetUint64(query.ToBlock.Uint64())
+	}
+
+	return ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(query.FromBlock.Uint64()),
+		ToBlock:   toBlock,
+		Addresses: addrs,
+		Topics:    topics,
+	}, nil
+}
+
+// fromGethLog converts a `go-ethereum` Log into the chain-agnostic Log
+// type returned by the multichain contract API.
+func fromGethLog(log types.Log) contract.Log {
+	topics := make([]pack.Bytes, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = pack.NewBytes(topic.Bytes())
+	}
+
+	return contract.Log{
+		Address:     address.Address(common.Address(log.Address).Hex()),
+		Topics:      topics,
+		Data:        pack.NewBytes(log.Data),
+		BlockHash:   pack.NewBytes(log.BlockHash.Bytes()),
+		BlockNumber: pack.NewU64(log.BlockNumber),
+		TxHash:      pack.NewBytes(log.TxHash.Bytes()),
+		TxIndex:     pack.NewU32(uint32(log.TxIndex)),
+		Index:       pack.NewU32(uint32(log.Index)),
+		Removed:     log.Removed,
+	}
+}
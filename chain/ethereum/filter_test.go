@@ -0,0 +1,93 @@
+package ethereum
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/renproject/multichain/api/address"
+	"github.com/renproject/multichain/api/contract"
+	"github.com/renproject/pack"
+)
+
+func TestToGethFilterQueryZeroToBlockIsLatest(t *testing.T) {
+	query := contract.LogFilterQuery{
+		FromBlock: pack.NewU64(10),
+		ToBlock:   pack.NewU64(0),
+	}
+
+	gethQuery, err := toGethFilterQuery(query)
+	if err != nil {
+		t.Fatalf("toGethFilterQuery returned error: %v", err)
+	}
+	if gethQuery.ToBlock != nil {
+		t.Fatalf("ToBlock = %v, want nil (latest)", gethQuery.ToBlock)
+	}
+	if gethQuery.FromBlock.Uint64() != 10 {
+		t.Fatalf("FromBlock = %v, want 10", gethQuery.FromBlock)
+	}
+}
+
+func TestToGethFilterQueryNonZeroToBlock(t *testing.T) {
+	query := contract.LogFilterQuery{
+		FromBlock: pack.NewU64(10),
+		ToBlock:   pack.NewU64(20),
+	}
+
+	gethQuery, err := toGethFilterQuery(query)
+	if err != nil {
+		t.Fatalf("toGethFilterQuery returned error: %v", err)
+	}
+	if gethQuery.ToBlock == nil || gethQuery.ToBlock.Uint64() != 20 {
+		t.Fatalf("ToBlock = %v, want 20", gethQuery.ToBlock)
+	}
+}
+
+func TestToGethFilterQueryRejectsBadAddress(t *testing.T) {
+	query := contract.LogFilterQuery{
+		Addresses: []address.Address{"not-an-address"},
+	}
+
+	if _, err := toGethFilterQuery(query); err == nil {
+		t.Fatal("toGethFilterQuery did not reject a malformed address")
+	}
+}
+
+func TestFromGethLog(t *testing.T) {
+	addr := common.HexToAddress("0xabc")
+	topic := common.HexToHash("0x1")
+	blockHash := common.HexToHash("0x2")
+	txHash := common.HexToHash("0x3")
+
+	log := types.Log{
+		Address:     addr,
+		Topics:      []common.Hash{topic},
+		Data:        []byte{1, 2, 3},
+		BlockHash:   blockHash,
+		BlockNumber: 42,
+		TxHash:      txHash,
+		Removed:     true,
+	}
+
+	got := fromGethLog(log)
+
+	if got.Address != address.Address(addr.Hex()) {
+		t.Errorf("Address = %v, want %v", got.Address, address.Address(addr.Hex()))
+	}
+	if len(got.Topics) != 1 || !bytes.Equal([]byte(got.Topics[0]), topic.Bytes()) {
+		t.Errorf("Topics = %v, want [%x]", got.Topics, topic.Bytes())
+	}
+	if !bytes.Equal([]byte(got.Data), log.Data) {
+		t.Errorf("Data = %v, want %x", got.Data, log.Data)
+	}
+	if !bytes.Equal([]byte(got.BlockHash), blockHash.Bytes()) {
+		t.Errorf("BlockHash = %v, want %x", got.BlockHash, blockHash.Bytes())
+	}
+	if got.BlockNumber.Uint64() != 42 {
+		t.Errorf("BlockNumber = %v, want 42", got.BlockNumber)
+	}
+	if !got.Removed {
+		t.Error("Removed = false, want true")
+	}
+}
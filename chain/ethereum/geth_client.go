@@ -0,0 +1,173 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/renproject/multichain/api/account"
+	"github.com/renproject/pack"
+)
+
+// GethClient is a companion to Client exposing geth-specific JSON-RPC
+// methods that fall outside the portable Backend interface: EIP-2930
+// access-list pre-flight (eth_createAccessList), structured transaction
+// tracing (debug_traceTransaction) and a mempool snapshot
+// (txpool_content). These namespaces aren't part of the standard Ethereum
+// JSON-RPC spec, aren't implemented by every node, and don't fit
+// Backend's failover/reconnect abstraction, so GethClient is tied to a
+// single geth-compatible node rather than routed through a Client.
+type GethClient struct {
+	rpc  *rpc.Client
+	geth *gethclient.Client
+
+	chainIDMu sync.Mutex
+	chainID   *big.Int
+}
+
+// NewGethClient dials rpcURL and returns a GethClient.
+func NewGethClient(rpcURL string) (*GethClient, error) {
+	rpcClient, err := rpc.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dialing url '%v': %v", rpcURL, err)
+	}
+	return NewGethClientWithRPC(rpcClient), nil
+}
+
+// NewGethClientWithRPC wraps an already-dialed *rpc.Client, for callers
+// that want to share a connection with an existing Client (e.g. one
+// obtained from the default Backend's ethclient.Client.Client() accessor).
+func NewGethClientWithRPC(rpcClient *rpc.Client) *GethClient {
+	return &GethClient{
+		rpc:  rpcClient,
+		geth: gethclient.New(rpcClient),
+	}
+}
+
+// cachedChainID returns the connected chain's ID, fetching it from the
+// node on the first call and caching it thereafter, mirroring
+// Client.cachedChainID.
+func (c *GethClient) cachedChainID(ctx context.Context) (*big.Int, error) {
+	c.chainIDMu.Lock()
+	defer c.chainIDMu.Unlock()
+
+	if c.chainID == nil {
+		var result hexutil.Big
+		if err := c.rpc.CallContext(ctx, &result, "eth_chainId"); err != nil {
+			return nil, err
+		}
+		c.chainID = (*big.Int)(&result)
+	}
+	return c.chainID, nil
+}
+
+// CreateAccessList asks the node to pre-flight msg under
+// eth_createAccessList and compute the EIP-2930 access list (and
+// resulting gas usage) it would need. A transaction built with that
+// access list warms the relevant storage slots ahead of time, instead of
+// paying cold-access gas costs when it's actually mined.
+func (c *GethClient) CreateAccessList(ctx context.Context, msg ethereum.CallMsg) (*types.AccessList, uint64, error) {
+	accessList, gasUsed, err := c.geth.CreateAccessList(ctx, msg)
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating access list: %v", err)
+	}
+	return accessList, gasUsed, nil
+}
+
+// TraceConfig configures TraceTransaction. A zero value traces with
+// geth's built-in "callTracer", which is sufficient to recover a revert
+// reason.
+type TraceConfig struct {
+	// Tracer names the built-in JS/native tracer to run (e.g.
+	// "callTracer", "prestateTracer"). Defaults to "callTracer".
+	Tracer string
+}
+
+// CallFrame is one frame of a structured call-frame trace, in the shape
+// produced by geth's built-in "callTracer": Calls holds the frames
+// initiated by this one (CALL, DELEGATECALL, CREATE, ...), recursively.
+type CallFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Input   hexutil.Bytes   `json:"input"`
+	Output  hexutil.Bytes   `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []CallFrame     `json:"calls,omitempty"`
+}
+
+// TraceTransaction returns the structured call-frame tree that
+// debug_traceTransaction produces for the given, already-mined
+// transaction. The root frame's Error field carries the revert reason
+// (e.g. "execution reverted: insufficient balance") when the transaction
+// failed, which Client.Tx and Client.TxBatch (given this GethClient via
+// Client.WithGethClient) include in their error in place of the opaque
+// "reverted, reciept status 0" one.
+func (c *GethClient) TraceTransaction(ctx context.Context, txID pack.Bytes, cfg TraceConfig) (*CallFrame, error) {
+	if cfg.Tracer == "" {
+		cfg.Tracer = "callTracer"
+	}
+
+	var frame CallFrame
+	if err := c.rpc.CallContext(ctx, &frame, "debug_traceTransaction", common.BytesToHash(txID), map[string]string{"tracer": cfg.Tracer}); err != nil {
+		return nil, fmt.Errorf("tracing tx '%v': %v", txID, err)
+	}
+	return &frame, nil
+}
+
+// revertReason traces txID and returns the revert reason reported by the
+// root call frame, or "" if the trace didn't report one (including when
+// tracing itself fails: with a missing debug_ namespace, Tx/TxBatch just
+// fall back to their opaque "reverted, reciept status 0" error).
+func (c *GethClient) revertReason(ctx context.Context, txID pack.Bytes) string {
+	frame, err := c.TraceTransaction(ctx, txID, TraceConfig{})
+	if err != nil {
+		return ""
+	}
+	return frame.Error
+}
+
+// txpoolContent mirrors the txpool_content RPC response. Transactions are
+// keyed first by sender address and then by nonce, both encoded as
+// strings by geth; PendingTransactions only needs the values.
+type txpoolContent struct {
+	Pending map[string]map[string]rpcTransaction `json:"pending"`
+}
+
+// PendingTransactions returns a snapshot of every transaction currently
+// sitting in the node's mempool ready to be included: the "pending" half
+// of txpool_content. Transactions blocked on a nonce gap ("queued") are
+// not yet executable and so are not included.
+func (c *GethClient) PendingTransactions(ctx context.Context) ([]account.Tx, error) {
+	chainID, err := c.cachedChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching chain ID: %v", err)
+	}
+
+	var content txpoolContent
+	if err := c.rpc.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, fmt.Errorf("fetching txpool content: %v", err)
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	txs := make([]account.Tx, 0, len(content.Pending))
+	for _, byNonce := range content.Pending {
+		for _, tx := range byNonce {
+			if tx.tx == nil {
+				continue
+			}
+			txs = append(txs, &Tx{ethTx: tx.tx, signer: signer})
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,63 @@
+package ethereum
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddrLocker is a per-address lock, so that concurrent goroutines
+// building transactions for the same sender don't race on nonce
+// assignment. It mirrors the AddrLocker used by the ethermint/laconicd
+// RPC backend to serialise `eth_sendTransaction`-style nonce handling.
+//
+// Unlike a plain map of `sync.Mutex`, Lock doesn't hand back a key the
+// caller passes to some other "unlock this address" entry point: it
+// returns a release func tied to that specific acquisition. That is
+// deliberate. An address-keyed unlock can't tell one acquisition of the
+// lock from another, so an unrelated caller (e.g. one resubmitting an
+// old signed tx for the same sender) could release a lock a different,
+// still in-flight acquisition depends on. Returning the release func
+// instead makes that impossible: the only way to unlock addr is to call
+// the func the matching Lock call produced.
+type AddrLocker struct {
+	mu    sync.Mutex
+	locks map[common.Address]chan struct{}
+}
+
+// NewAddrLocker returns an unlocked AddrLocker.
+func NewAddrLocker() *AddrLocker {
+	return &AddrLocker{
+		locks: map[common.Address]chan struct{}{},
+	}
+}
+
+// slot returns the (lazily created) lock channel for addr. The channel
+// holds a token when the address is unlocked, and is empty while locked.
+func (l *AddrLocker) slot(addr common.Address) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ch, ok := l.locks[addr]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		ch <- struct{}{}
+		l.locks[addr] = ch
+	}
+	return ch
+}
+
+// Lock blocks until addr is available, then locks it and returns a
+// release func that unlocks it again. release is idempotent: calling it
+// more than once only unlocks addr on the first call, so callers can
+// unconditionally `defer release()` even on a path that has already
+// called it explicitly to unlock early.
+func (l *AddrLocker) Lock(addr common.Address) (release func()) {
+	ch := l.slot(addr)
+	<-ch
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { ch <- struct{}{} })
+	}
+}
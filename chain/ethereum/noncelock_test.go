@@ -0,0 +1,76 @@
+package ethereum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAddrLockerExcludesConcurrentHolders(t *testing.T) {
+	locker := NewAddrLocker()
+	addr := common.HexToAddress("0x1")
+
+	release := locker.Lock(addr)
+
+	acquired := make(chan struct{})
+	go func() {
+		locker.Lock(addr)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Lock returned while the first holder had not released")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second Lock never returned after the first holder released")
+	}
+}
+
+func TestAddrLockerReleaseIsIdempotent(t *testing.T) {
+	locker := NewAddrLocker()
+	addr := common.HexToAddress("0x2")
+
+	release := locker.Lock(addr)
+	release()
+	release()
+
+	done := make(chan struct{})
+	go func() {
+		locker.Lock(addr)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Lock never returned; a double release corrupted the lock state")
+	}
+}
+
+func TestAddrLockerIndependentAddresses(t *testing.T) {
+	locker := NewAddrLocker()
+
+	release1 := locker.Lock(common.HexToAddress("0x1"))
+	defer release1()
+
+	done := make(chan struct{})
+	go func() {
+		release2 := locker.Lock(common.HexToAddress("0x2"))
+		release2()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("locking a different address blocked on an unrelated address's lock")
+	}
+}
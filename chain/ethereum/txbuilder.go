@@ -0,0 +1,177 @@
+package ethereum
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/renproject/id"
+	"github.com/renproject/multichain/api/account"
+	"github.com/renproject/multichain/api/address"
+	"github.com/renproject/pack"
+)
+
+// gasTipCapMultiplier pads the node-suggested priority fee so that the
+// built transaction still confirms promptly if the tip moves between
+// estimation and broadcast.
+const gasTipCapMultiplier = 2
+
+// NOTE: Tx's own Serialize/Deserialize (the account.Tx methods a signed
+// dynamic-fee transaction round-trips through) aren't defined anywhere
+// in this package - the Tx type this file constructs is declared
+// elsewhere in the module. This series cannot confirm from here that
+// encode/decode preserves the EIP-2718 type byte for a DynamicFeeTx; if
+// Tx's Serialize/Deserialize don't already delegate to
+// (*types.Transaction).MarshalBinary/UnmarshalBinary, they need to, or a
+// type-0x2 transaction built by BuildTx will not survive a round trip.
+
+// TxBuilder builds fee-market-aware ethereum transactions, implementing
+// the account.TxBuilder interface. Unlike a plain TxBuilder that expects
+// the caller to have already priced the transaction, this implementation
+// treats a zero gas/gasTipCap/gasFeeCap as "estimate it for me": it
+// queries the node for a suggested priority fee, the latest block's base
+// fee, and an `eth_estimateGas` gas limit, so that callers don't need to
+// do their own gas math.
+type TxBuilder struct {
+	client *Client
+	geth   *GethClient
+}
+
+// NewTxBuilder returns a TxBuilder that estimates gas using client.
+func NewTxBuilder(client *Client) *TxBuilder {
+	return &TxBuilder{client: client}
+}
+
+// NewTxBuilderWithGethClient returns a TxBuilder that additionally
+// pre-flights every built transaction through geth's eth_createAccessList,
+// attaching the resulting EIP-2930 access list to the transaction and
+// using its gas usage as the estimate, so the built transaction doesn't
+// pay for cold storage access it has already warmed.
+func NewTxBuilderWithGethClient(client *Client, geth *GethClient) *TxBuilder {
+	return &TxBuilder{client: client, geth: geth}
+}
+
+// BuildTx builds an unsigned transaction from the given nonce, recipient,
+// value and payload. It produces an EIP-1559 dynamic-fee transaction
+// (type 0x2) when the chain's latest header advertises a base fee,
+// falling back to a legacy transaction on chains that have not activated
+// EIP-1559 (where baseFee is nil). gas, gasTipCap and gasFeeCap are used
+// as given when non-zero (on the legacy path gasFeeCap is the gas price
+// and gasTipCap is not used); a zero value for any of them is estimated from
+// the node instead, via a call made as fromPubKey's address, so that
+// access-list/gas-estimation logic gated on msg.sender (balance/
+// allowance checks, access control) sees the actual sender rather than
+// the zero address.
+func (b *TxBuilder) BuildTx(ctx context.Context, fromPubKey *id.PubKey, to address.Address, value, nonce, gas, gasTipCap, gasFeeCap pack.U256, payload pack.Bytes) (account.Tx, error) {
+	targetAddr, err := NewAddressFromHex(string(pack.String(to)))
+	if err != nil {
+		return nil, fmt.Errorf("bad to address '%v': %v", to, err)
+	}
+	toAddr := common.Address(targetAddr)
+	fromAddr := crypto.PubkeyToAddress(*(*ecdsa.PublicKey)(fromPubKey))
+
+	chainID, err := b.client.cachedChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetching chain ID: %v", err)
+	}
+
+	header, err := b.client.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching header: %v", err)
+	}
+
+	// If geth is set, pre-flight the call to pick up the EIP-2930 access
+	// list it would need, so the built transaction warms those storage
+	// slots upfront, and reuse its gas usage as the estimate rather than
+	// issuing a second, plain eth_estimateGas call.
+	var accessList types.AccessList
+	gasLimit := gas.Int().Uint64()
+	if b.geth != nil {
+		list, gasUsed, err := b.geth.CreateAccessList(ctx, ethereum.CallMsg{
+			From:  fromAddr,
+			To:    &toAddr,
+			Value: value.Int(),
+			Data:  payload,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating access list: %v", err)
+		}
+		if list != nil {
+			accessList = *list
+		}
+		if gasLimit == 0 {
+			gasLimit = gasUsed
+		}
+	}
+	if gasLimit == 0 {
+		estimated, err := b.client.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  fromAddr,
+			To:    &toAddr,
+			Value: value.Int(),
+			Data:  payload,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("estimating gas: %v", err)
+		}
+		gasLimit = estimated
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+
+	// Pre-London chains (and forks that never activated EIP-1559) report
+	// a nil base fee; fall back to a legacy transaction in that case.
+	if header.BaseFee == nil {
+		gasPrice := gasFeeCap.Int()
+		if gasPrice.Sign() == 0 {
+			gasPrice, err = b.client.backend.SuggestGasPrice(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("suggesting gas price: %v", err)
+			}
+		}
+		ethTx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce.Int().Uint64(),
+			To:       &toAddr,
+			Value:    value.Int(),
+			Gas:      gasLimit,
+			GasPrice: gasPrice,
+			Data:     payload,
+		})
+		return &Tx{ethTx: ethTx, signer: signer}, nil
+	}
+
+	tipCap := gasTipCap.Int()
+	if tipCap.Sign() == 0 {
+		tipCap, err = b.client.backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("suggesting gas tip cap: %v", err)
+		}
+		tipCap = new(big.Int).Mul(tipCap, big.NewInt(gasTipCapMultiplier))
+	}
+
+	feeCap := gasFeeCap.Int()
+	if feeCap.Sign() == 0 {
+		// MaxFeePerGas covers one base fee doubling on top of the tip,
+		// which tolerates about six consecutive full blocks (+12.5% base
+		// fee each) before the transaction goes stale.
+		feeCap = new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tipCap)
+	}
+
+	ethTx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      nonce.Int().Uint64(),
+		To:         &toAddr,
+		Value:      value.Int(),
+		Gas:        gasLimit,
+		GasTipCap:  tipCap,
+		GasFeeCap:  feeCap,
+		Data:       payload,
+		AccessList: accessList,
+	})
+
+	return &Tx{ethTx: ethTx, signer: signer}, nil
+}
@@ -0,0 +1,323 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultWSMaxBackoff caps the exponential backoff between reconnect
+// attempts so a long node outage doesn't end up waiting hours between
+// tries.
+const defaultWSMaxBackoff = 30 * time.Second
+
+// WSBackend is a Backend implementation that dials a WebSocket endpoint
+// and transparently redials, with exponential backoff, whenever a call
+// fails because the connection was dropped. It is the transport required
+// for subscriptions (SubscribeLogs, SubscribeNewHeads), which the plain
+// HTTP-dialed `ethclient.Client` cannot support.
+type WSBackend struct {
+	url        string
+	maxBackoff time.Duration
+
+	mu     sync.RWMutex
+	client *ethclient.Client
+
+	// reconnectMu serialises redials, so that concurrent callers who all
+	// observe the same dropped connection coalesce onto a single redial
+	// instead of each racing their own and leaking a connection per
+	// loser (see reconnect).
+	reconnectMu sync.Mutex
+}
+
+// NewWSBackend dials url (which must be a `ws://` or `wss://` endpoint)
+// and returns a WSBackend that reconnects automatically on failure.
+func NewWSBackend(url string) (*WSBackend, error) {
+	backend := &WSBackend{
+		url:        url,
+		maxBackoff: defaultWSMaxBackoff,
+	}
+	if err := backend.dial(); err != nil {
+		return nil, fmt.Errorf("dialing url '%v': %v", url, err)
+	}
+	return backend, nil
+}
+
+// dial redials b.url and swaps it in as the current connection, closing
+// out the connection it replaces (if any) so a redial never leaks the
+// stale websocket.
+func (b *WSBackend) dial() error {
+	rpcClient, err := rpc.Dial(b.url)
+	if err != nil {
+		return err
+	}
+	newClient := ethclient.NewClient(rpcClient)
+
+	b.mu.Lock()
+	stale := b.client
+	b.client = newClient
+	b.mu.Unlock()
+
+	if stale != nil {
+		stale.Close()
+	}
+	return nil
+}
+
+func (b *WSBackend) current() *ethclient.Client {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.client
+}
+
+// Close releases the underlying websocket connection. A WSBackend must
+// not be used after Close returns.
+func (b *WSBackend) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.client != nil {
+		b.client.Close()
+	}
+}
+
+// reconnect redials the node with exponential backoff, until it succeeds
+// or ctx is done. stale is the connection the caller observed failing:
+// if b.client has already moved past it by the time reconnect acquires
+// reconnectMu, some other caller redialed first, and reconnect returns
+// immediately without dialing again.
+func (b *WSBackend) reconnect(ctx context.Context, stale *ethclient.Client) error {
+	b.reconnectMu.Lock()
+	defer b.reconnectMu.Unlock()
+
+	if b.current() != stale {
+		return nil
+	}
+
+	backoff := time.Second
+	for {
+		if err := b.dial(); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > b.maxBackoff {
+			backoff = b.maxBackoff
+		}
+	}
+}
+
+// isDisconnected reports whether err looks like it was caused by the
+// underlying websocket connection being closed, as opposed to a normal
+// "the call completed but the answer is an error" result. A node that
+// returns a proper JSON-RPC error implements rpc.Error (e.g. "execution
+// reverted"), and ethereum.NotFound is the sentinel `ethclient.Client`
+// uses for an ordinary "doesn't exist (yet)" lookup (a tx not yet
+// broadcast or mined) — neither says anything about the transport, so
+// both are routine results, not a dropped connection. What's left after
+// excluding those is a raw transport failure: closed connection, EOF, a
+// read/write erroring out on a dead socket.
+func isDisconnected(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	if errors.Is(err, rpc.ErrClientQuit) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// withReconnect calls fn against the current connection, and retries
+// once against a freshly redialed connection if fn failed because the
+// connection was dropped.
+func (b *WSBackend) withReconnect(ctx context.Context, fn func(*ethclient.Client) error) error {
+	current := b.current()
+	err := fn(current)
+	if err != nil && isDisconnected(err) {
+		if rerr := b.reconnect(ctx, current); rerr == nil {
+			return fn(b.current())
+		}
+	}
+	return err
+}
+
+// HeaderByNumber implements Backend.
+func (b *WSBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+// TransactionByHash implements Backend.
+func (b *WSBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	var (
+		tx      *types.Transaction
+		pending bool
+	)
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		tx, pending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, pending, err
+}
+
+// TransactionReceipt implements Backend.
+func (b *WSBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, hash)
+		return err
+	})
+	return receipt, err
+}
+
+// SendTransaction implements Backend.
+func (b *WSBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.withReconnect(ctx, func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+// NonceAt implements Backend.
+func (b *WSBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var nonce uint64
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		nonce, err = client.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return nonce, err
+}
+
+// PendingNonceAt implements Backend.
+func (b *WSBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var nonce uint64
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+// BalanceAt implements Backend.
+func (b *WSBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		balance, err = client.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+// CallContract implements Backend.
+func (b *WSBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var data []byte
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		data, err = client.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return data, err
+}
+
+// ChainID implements Backend.
+func (b *WSBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	var chainID *big.Int
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		chainID, err = client.ChainID(ctx)
+		return err
+	})
+	return chainID, err
+}
+
+// EstimateGas implements Backend.
+func (b *WSBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var gas uint64
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		gas, err = client.EstimateGas(ctx, msg)
+		return err
+	})
+	return gas, err
+}
+
+// SuggestGasPrice implements Backend.
+func (b *WSBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var price *big.Int
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		price, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+// SuggestGasTipCap implements Backend.
+func (b *WSBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var tip *big.Int
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+// FilterLogs implements Backend.
+func (b *WSBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := b.withReconnect(ctx, func(client *ethclient.Client) (err error) {
+		logs, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+// BatchCallContext implements BatchBackend, delegating to the *rpc.Client
+// the current connection was dialed from, with the same reconnect-and-
+// retry-once behaviour as the other methods.
+func (b *WSBackend) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	current := b.current()
+	err := current.Client().BatchCallContext(ctx, batch)
+	if err != nil && isDisconnected(err) {
+		if rerr := b.reconnect(ctx, current); rerr == nil {
+			return b.current().Client().BatchCallContext(ctx, batch)
+		}
+	}
+	return err
+}
+
+// SubscribeFilterLogs implements Backend. Unlike the other methods, a
+// dropped subscription is not silently retried: the caller observes the
+// subscription's Err() channel close and is expected to re-subscribe,
+// since any logs missed while disconnected cannot be replayed onto ch.
+func (b *WSBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return b.current().SubscribeFilterLogs(ctx, query, ch)
+}
+
+// SubscribeNewHead implements Backend. See SubscribeFilterLogs for why
+// reconnects are not transparently retried here.
+func (b *WSBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return b.current().SubscribeNewHead(ctx, ch)
+}
@@ -0,0 +1,45 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// jsonRPCError is a minimal rpc.Error implementation, standing in for
+// the error a node returns for a properly-answered call (e.g.
+// "execution reverted").
+type jsonRPCError struct{ msg string }
+
+func (e jsonRPCError) Error() string  { return e.msg }
+func (e jsonRPCError) ErrorCode() int { return -32000 }
+
+func TestIsDisconnected(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", ethereum.NotFound, false},
+		{"wrapped not found", fmt.Errorf("fetching tx: %w", ethereum.NotFound), false},
+		{"rpc error", jsonRPCError{"execution reverted"}, false},
+		{"client quit", rpc.ErrClientQuit, true},
+		{"context canceled", context.Canceled, true},
+		{"eof", io.EOF, true},
+		{"raw transport error", errors.New("use of closed network connection"), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isDisconnected(c.err); got != c.want {
+				t.Errorf("isDisconnected(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}